@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config defines the configuration options that can be provided to
+// the predictive horizontal pod autoscaler, parsed from the PHPA resource.
+package config
+
+import "time"
+
+// Scope restricts a model to only producing predictions for a particular
+// direction of replica change.
+type Scope string
+
+const (
+	// ScopeScaleUp restricts a model to only apply when it would increase
+	// the replica count.
+	ScopeScaleUp Scope = "ScaleUp"
+	// ScopeScaleDown restricts a model to only apply when it would decrease
+	// the replica count.
+	ScopeScaleDown Scope = "ScaleDown"
+	// ScopeBoth allows a model to apply regardless of the direction of
+	// replica change. This is the default scope.
+	ScopeBoth Scope = "Both"
+)
+
+// Model represents the configuration of a prediction model, for example a
+// linear regression model or a Holt-Winters exponential smoothing model.
+type Model struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// Scope restricts the model to only producing predictions that scale up,
+	// only those that scale down, or both (the default).
+	Scope      Scope       `json:"scope,omitempty"`
+	Linear     *Linear     `json:"linear,omitempty"`
+	Prometheus *Prometheus `json:"prometheus,omitempty"`
+	// CacheTTL is how long a prediction result is cached for before it must
+	// be recalculated. Defaults to no caching if not set.
+	CacheTTL time.Duration `json:"cacheTTL,omitempty"`
+	// CacheSize is the maximum number of predictions to keep cached for
+	// this model at once, evicting the least recently used entry once
+	// exceeded.
+	CacheSize int `json:"cacheSize,omitempty"`
+}
+
+// Linear is the configuration options for a linear regression prediction
+// model.
+type Linear struct {
+	StoredValues int `json:"storedValues"`
+	LookAhead    int `json:"lookAhead"`
+	// RSquaredThreshold is the minimum acceptable R² goodness-of-fit for the
+	// regression. If the algorithm reports a lower value the prediction is
+	// still returned, but flagged with an AnnotationRegressionFit
+	// annotation. Defaults to no check if not set.
+	RSquaredThreshold float64 `json:"rSquaredThreshold,omitempty"`
+	// MinReplicas and MaxReplicas clamp the prediction to a bound, flagging
+	// an AnnotationValueClamped annotation when the raw prediction fell
+	// outside of it. Defaults to no clamping if not set.
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+}
+
+// Prometheus configures a Prometheus instance that can be queried to seed a
+// model's training data from historical metrics, rather than relying solely
+// on evaluations stored locally over time.
+type Prometheus struct {
+	// Host is the address of the Prometheus instance to query, e.g.
+	// "http://prometheus.monitoring.svc.cluster.local:9090".
+	Host string `json:"host"`
+	// Query is the PromQL query to run to retrieve the historical values.
+	Query string `json:"query"`
+	// Step is the resolution of the returned range query.
+	Step time.Duration `json:"step"`
+	// Lookback is how far back in time to query for historical values.
+	Lookback time.Duration `json:"lookback"`
+	// Timeout bounds how long to wait for the range query to complete.
+	// Defaults to 30 seconds if not set.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Auth holds the optional credentials to use when querying Prometheus.
+	Auth *PrometheusAuth `json:"auth,omitempty"`
+}
+
+// PrometheusAuth configures authentication for querying a Prometheus
+// instance, mirroring the Prometheus remote-read authentication options.
+type PrometheusAuth struct {
+	BearerToken string               `json:"bearerToken,omitempty"`
+	BasicAuth   *PrometheusBasicAuth `json:"basicAuth,omitempty"`
+	TLS         *PrometheusTLSConfig `json:"tls,omitempty"`
+}
+
+// PrometheusBasicAuth configures HTTP basic authentication for Prometheus.
+type PrometheusBasicAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// PrometheusTLSConfig configures TLS client authentication for Prometheus.
+type PrometheusTLSConfig struct {
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+	CertFile           string `json:"certFile,omitempty"`
+	KeyFile            string `json:"keyFile,omitempty"`
+	CAFile             string `json:"caFile,omitempty"`
+}
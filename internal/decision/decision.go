@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package decision combines the predictions produced by multiple models
+// configured on a PredictiveHorizontalPodAutoscaler into a single target
+// replica count.
+package decision
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
+)
+
+const (
+	// TypeMax combines predictions by taking the highest value.
+	TypeMax = "Max"
+	// TypeMin combines predictions by taking the lowest value.
+	TypeMin = "Min"
+	// TypeMean combines predictions by taking the mean value.
+	TypeMean = "Mean"
+)
+
+// ModelResult pairs a single model's prediction with any annotations it
+// produced, so that models skipped by their configured Scope can be
+// excluded from the combined result rather than treated as a zero value.
+type ModelResult struct {
+	Prediction  int32
+	Annotations []prediction.Annotation
+}
+
+// Combine merges the results of multiple prediction models into a single
+// target replica count, using the provided calculation type. Models that
+// were skipped, for example because their Scope did not match the
+// direction of change proposed, are excluded from the combination.
+func Combine(calculationType string, results []ModelResult) (int32, error) {
+	values := make([]int32, 0, len(results))
+	for _, result := range results {
+		if skipped(result.Annotations) {
+			continue
+		}
+		values = append(values, result.Prediction)
+	}
+
+	if len(values) == 0 {
+		return 0, errors.New("No model predictions available to combine")
+	}
+
+	switch calculationType {
+	case TypeMax:
+		return maxInt32(values), nil
+	case TypeMin:
+		return minInt32(values), nil
+	case TypeMean:
+		return meanInt32(values), nil
+	default:
+		return 0, fmt.Errorf("Unknown calculation type '%s'", calculationType)
+	}
+}
+
+func skipped(annotations []prediction.Annotation) bool {
+	for _, annotation := range annotations {
+		if annotation.Kind == prediction.AnnotationModelSkipped {
+			return true
+		}
+	}
+	return false
+}
+
+func maxInt32(values []int32) int32 {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value > result {
+			result = value
+		}
+	}
+	return result
+}
+
+func minInt32(values []int32) int32 {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
+
+func meanInt32(values []int32) int32 {
+	var sum int64
+	for _, value := range values {
+		sum += int64(value)
+	}
+	return int32(sum / int64(len(values)))
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decision_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/decision"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
+)
+
+func TestCombine(t *testing.T) {
+	equateErrorMessage := cmp.Comparer(func(x, y error) bool {
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		return x.Error() == y.Error()
+	})
+
+	var tests = []struct {
+		description     string
+		expected        int32
+		expectedErr     error
+		calculationType string
+		results         []decision.ModelResult
+	}{
+		{
+			"Fail unknown calculation type",
+			0,
+			errors.New("Unknown calculation type 'Unknown'"),
+			"Unknown",
+			[]decision.ModelResult{
+				{Prediction: 3},
+			},
+		},
+		{
+			"Fail all models skipped",
+			0,
+			errors.New("No model predictions available to combine"),
+			decision.TypeMax,
+			[]decision.ModelResult{
+				{
+					Prediction:  3,
+					Annotations: []prediction.Annotation{{Kind: prediction.AnnotationModelSkipped}},
+				},
+			},
+		},
+		{
+			"Success max, ignoring skipped model",
+			7,
+			nil,
+			decision.TypeMax,
+			[]decision.ModelResult{
+				{Prediction: 3},
+				{
+					Prediction:  9,
+					Annotations: []prediction.Annotation{{Kind: prediction.AnnotationModelSkipped}},
+				},
+				{Prediction: 7},
+			},
+		},
+		{
+			"Success min, ignoring skipped model",
+			3,
+			nil,
+			decision.TypeMin,
+			[]decision.ModelResult{
+				{
+					Prediction:  1,
+					Annotations: []prediction.Annotation{{Kind: prediction.AnnotationModelSkipped}},
+				},
+				{Prediction: 3},
+				{Prediction: 7},
+			},
+		},
+		{
+			"Success mean, ignoring skipped model",
+			5,
+			nil,
+			decision.TypeMean,
+			[]decision.ModelResult{
+				{Prediction: 3},
+				{Prediction: 7},
+				{
+					Prediction:  100,
+					Annotations: []prediction.Annotation{{Kind: prediction.AnnotationModelSkipped}},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := decision.Combine(test.calculationType, test.results)
+			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
+				return
+			}
+			if !cmp.Equal(test.expected, result) {
+				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
+			}
+		})
+	}
+}
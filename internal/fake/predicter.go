@@ -0,0 +1,45 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/config"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/stored"
+)
+
+// Predicter (fake) provides a way to insert functionality into a Predicter
+type Predicter struct {
+	GetIDsToRemoveReactor func(model *config.Model, evaluations []*stored.Evaluation) ([]int, error)
+	GetPredictionReactor  func(model *config.Model, evaluations []*stored.Evaluation) (int32, []prediction.Annotation, error)
+	GetTypeReactor        func() string
+}
+
+// GetIDsToRemove calls the fake Predicter function
+func (f *Predicter) GetIDsToRemove(model *config.Model, evaluations []*stored.Evaluation) ([]int, error) {
+	return f.GetIDsToRemoveReactor(model, evaluations)
+}
+
+// GetPrediction calls the fake Predicter function
+func (f *Predicter) GetPrediction(model *config.Model, evaluations []*stored.Evaluation) (int32, []prediction.Annotation, error) {
+	return f.GetPredictionReactor(model, evaluations)
+}
+
+// GetType calls the fake Predicter function
+func (f *Predicter) GetType() string {
+	return f.GetTypeReactor()
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import "k8s.io/apimachinery/pkg/runtime"
+
+// Recorder (fake) provides a way to insert functionality into an
+// EventRecorder
+type Recorder struct {
+	EventReactor func(object runtime.Object, eventtype, reason, message string)
+}
+
+// Event calls the fake Recorder function
+func (f *Recorder) Event(object runtime.Object, eventtype, reason, message string) {
+	f.EventReactor(object, eventtype, reason, message)
+}
+
+// Eventf calls the fake Recorder function
+func (f *Recorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	f.EventReactor(object, eventtype, reason, messageFmt)
+}
+
+// AnnotatedEventf calls the fake Recorder function
+func (f *Recorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	f.EventReactor(object, eventtype, reason, messageFmt)
+}
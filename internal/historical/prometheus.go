@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package historical provides a way to seed prediction models with data
+// from an external historical source, rather than relying solely on
+// evaluations accumulated locally over time. This allows a model to train
+// against days of real data immediately after startup instead of waiting
+// for enough evaluations to be stored.
+package historical
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/config"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/stored"
+)
+
+// defaultQueryTimeout bounds how long a range query is allowed to take when
+// a model's Prometheus configuration does not set its own Timeout, so that
+// an unreachable or slow Prometheus instance can't block a reconcile
+// indefinitely.
+const defaultQueryTimeout = 30 * time.Second
+
+// Gatherer retrieves historical evaluations from an external source, used
+// to seed prediction models before enough evaluations have been stored
+// locally.
+type Gatherer interface {
+	Gather(prometheus *config.Prometheus) ([]*stored.Evaluation, error)
+}
+
+// PrometheusGatherer queries a Prometheus instance over a range to build a
+// history of target replica counts, adapting the resulting time series into
+// stored evaluations.
+type PrometheusGatherer struct{}
+
+// Gather runs the configured PromQL range query against Prometheus and
+// converts the returned time series into evaluations, ordered oldest first.
+func (g *PrometheusGatherer) Gather(prometheus *config.Prometheus) ([]*stored.Evaluation, error) {
+	if prometheus == nil {
+		return nil, errors.New("No Prometheus configuration provided")
+	}
+
+	client, err := newClient(prometheus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Prometheus client: %w", err)
+	}
+
+	timeout := prometheus.Timeout
+	if timeout <= 0 {
+		timeout = defaultQueryTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	now := time.Now()
+	result, _, err := promv1.NewAPI(client).QueryRange(ctx, prometheus.Query, promv1.Range{
+		Start: now.Add(-prometheus.Lookback),
+		End:   now,
+		Step:  prometheus.Step,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Prometheus: %w", err)
+	}
+
+	matrix, ok := result.(model.Matrix)
+	if !ok || len(matrix) == 0 {
+		return nil, nil
+	}
+
+	samples := matrix[0].Values
+	evaluations := make([]*stored.Evaluation, len(samples))
+	for i, pair := range samples {
+		// Historical evaluations are assigned negative IDs so they can
+		// never collide with the monotonically increasing IDs assigned to
+		// evaluations stored locally by the database.
+		evaluations[i] = &stored.Evaluation{
+			ID:      i - len(samples),
+			Created: pair.Timestamp.Time(),
+			Evaluation: stored.DBEvaluation{
+				TargetReplicas: int32(pair.Value),
+			},
+		}
+	}
+
+	return evaluations, nil
+}
+
+func newClient(prometheus *config.Prometheus) (api.Client, error) {
+	roundTripper := api.DefaultRoundTripper
+
+	if prometheus.Auth != nil {
+		if prometheus.Auth.TLS != nil {
+			transport, err := tlsRoundTripper(prometheus.Auth.TLS)
+			if err != nil {
+				return nil, fmt.Errorf("failed to configure Prometheus TLS: %w", err)
+			}
+			roundTripper = transport
+		}
+
+		roundTripper = &authRoundTripper{auth: prometheus.Auth, next: roundTripper}
+	}
+
+	return api.NewClient(api.Config{
+		Address:      prometheus.Host,
+		RoundTripper: roundTripper,
+	})
+}
+
+// tlsRoundTripper builds an http.RoundTripper configured with the client
+// certificate, CA certificate and verification settings requested in the
+// provided TLS configuration.
+func tlsRoundTripper(tlsConfig *config.PrometheusTLSConfig) (http.RoundTripper, error) {
+	clientTLSConfig := &tls.Config{
+		InsecureSkipVerify: tlsConfig.InsecureSkipVerify,
+	}
+
+	if tlsConfig.CertFile != "" && tlsConfig.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(tlsConfig.CertFile, tlsConfig.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate/key: %w", err)
+		}
+		clientTLSConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if tlsConfig.CAFile != "" {
+		ca, err := os.ReadFile(tlsConfig.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("failed to parse CA certificate %s", tlsConfig.CAFile)
+		}
+		clientTLSConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = clientTLSConfig
+
+	return transport, nil
+}
+
+// authRoundTripper attaches the configured bearer or basic auth credentials
+// to every request made to Prometheus.
+type authRoundTripper struct {
+	auth *config.PrometheusAuth
+	next http.RoundTripper
+}
+
+func (a *authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch {
+	case a.auth.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+a.auth.BearerToken)
+	case a.auth.BasicAuth != nil:
+		req.SetBasicAuth(a.auth.BasicAuth.Username, a.auth.BasicAuth.Password)
+	}
+
+	return a.next.RoundTrip(req)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package historical_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/config"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/historical"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/stored"
+)
+
+// matrixResponse is a canned Prometheus query_range response containing a
+// single time series with two samples.
+const matrixResponse = `{
+	"status": "success",
+	"data": {
+		"resultType": "matrix",
+		"result": [
+			{
+				"metric": {},
+				"values": [[1000000000, "3"], [1000000060, "5"]]
+			}
+		]
+	}
+}`
+
+func TestPrometheusGatherer_Gather(t *testing.T) {
+	var tests = []struct {
+		description string
+		expectedErr string
+		prometheus  *config.Prometheus
+	}{
+		{
+			"Fail no Prometheus configuration",
+			"No Prometheus configuration provided",
+			nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			gatherer := &historical.PrometheusGatherer{}
+			_, err := gatherer.Gather(test.prometheus)
+			if err == nil || err.Error() != test.expectedErr {
+				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err))
+			}
+		})
+	}
+}
+
+func TestPrometheusGatherer_Gather_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, matrixResponse)
+	}))
+	defer server.Close()
+
+	gatherer := &historical.PrometheusGatherer{}
+	result, err := gatherer.Gather(&config.Prometheus{
+		Host:     server.URL,
+		Query:    "up",
+		Step:     time.Minute,
+		Lookback: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Samples are assigned negative IDs ordered oldest first, so they can
+	// never collide with the monotonically increasing IDs assigned to
+	// evaluations stored locally.
+	expected := []*stored.Evaluation{
+		{
+			ID:      -2,
+			Created: time.Unix(1000000000, 0),
+			Evaluation: stored.DBEvaluation{
+				TargetReplicas: 3,
+			},
+		},
+		{
+			ID:      -1,
+			Created: time.Unix(1000000060, 0),
+			Evaluation: stored.DBEvaluation{
+				TargetReplicas: 5,
+			},
+		},
+	}
+	if !cmp.Equal(expected, result) {
+		t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(expected, result))
+	}
+}
+
+func TestPrometheusGatherer_Gather_Auth(t *testing.T) {
+	var tests = []struct {
+		description string
+		auth        *config.PrometheusAuth
+		checkHeader func(t *testing.T, r *http.Request)
+	}{
+		{
+			"Bearer token sent as Authorization header",
+			&config.PrometheusAuth{
+				BearerToken: "some-token",
+			},
+			func(t *testing.T, r *http.Request) {
+				if header := r.Header.Get("Authorization"); header != "Bearer some-token" {
+					t.Errorf("Authorization header mismatch, expected %q, got %q", "Bearer some-token", header)
+				}
+			},
+		},
+		{
+			"Basic auth credentials sent as Authorization header",
+			&config.PrometheusAuth{
+				BasicAuth: &config.PrometheusBasicAuth{
+					Username: "user",
+					Password: "pass",
+				},
+			},
+			func(t *testing.T, r *http.Request) {
+				username, password, ok := r.BasicAuth()
+				if !ok {
+					t.Fatal("expected basic auth credentials to be set")
+				}
+				if username != "user" || password != "pass" {
+					t.Errorf("basic auth mismatch, expected user:pass, got %s:%s", username, password)
+				}
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var received *http.Request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, matrixResponse)
+			}))
+			defer server.Close()
+
+			gatherer := &historical.PrometheusGatherer{}
+			_, err := gatherer.Gather(&config.Prometheus{
+				Host:     server.URL,
+				Query:    "up",
+				Step:     time.Minute,
+				Lookback: time.Hour,
+				Auth:     test.auth,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			test.checkHeader(t, received)
+		})
+	}
+}
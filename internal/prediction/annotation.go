@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prediction
+
+// AnnotationKind categorises the non-fatal diagnostic raised by a
+// prediction model run, used to label the associated Kubernetes Event and
+// Prometheus metric.
+type AnnotationKind string
+
+const (
+	// AnnotationExtrapolated marks a prediction made with fewer stored
+	// evaluations than the model's configured StoredValues, meaning the
+	// result was extrapolated from a smaller sample than intended.
+	AnnotationExtrapolated AnnotationKind = "Extrapolated"
+	// AnnotationModelSkipped marks a prediction that was discarded because
+	// the direction of change it proposed fell outside the model's
+	// configured Scope, with the current replica count returned unchanged
+	// in its place.
+	AnnotationModelSkipped AnnotationKind = "ModelSkipped"
+	// AnnotationRegressionFit marks a prediction produced by a regression
+	// whose goodness-of-fit (R²) fell below the model's configured
+	// RSquaredThreshold, meaning the result may be unreliable.
+	AnnotationRegressionFit AnnotationKind = "RegressionFit"
+	// AnnotationLookAheadExceedsRange marks a prediction that projects
+	// further into the future (LookAhead) than the number of evaluations
+	// observed so far, meaning the model is forecasting beyond the range it
+	// has been trained on.
+	AnnotationLookAheadExceedsRange AnnotationKind = "LookAheadExceedsRange"
+	// AnnotationValueClamped marks a prediction that was clamped to the
+	// model's configured MinReplicas or MaxReplicas bound.
+	AnnotationValueClamped AnnotationKind = "ValueClamped"
+)
+
+// Annotation is a non-fatal diagnostic produced during a prediction model
+// run. Annotations are surfaced as Kubernetes Events on the PHPA resource
+// and as a Prometheus metric labelled by kind, giving operators insight
+// into why a predictor is behaving unexpectedly without requiring debug
+// logging.
+type Annotation struct {
+	Kind    AnnotationKind `json:"kind"`
+	Message string         `json:"message"`
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cache wraps a prediction.Predicter with a keyed, TTL-bounded LRU
+// cache, avoiding repeated expensive algorithm runs (e.g. forking a Python
+// process) for an evaluation set that has already been predicted.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/config"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/stored"
+)
+
+// cacheMetricLabels are the labels shared by every cache metric. Multiple
+// models can share the same Type (e.g. two differently scoped Linear
+// models), so model_name is included to keep each model's series distinct.
+var cacheMetricLabels = []string{"model_type", "model_name"}
+
+var (
+	cacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "predictive_hpa_prediction_cache_hits_total",
+		Help: "Number of prediction cache hits, by model type and name.",
+	}, cacheMetricLabels)
+	cacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "predictive_hpa_prediction_cache_misses_total",
+		Help: "Number of prediction cache misses, by model type and name.",
+	}, cacheMetricLabels)
+	cacheEvictions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "predictive_hpa_prediction_cache_evictions_total",
+		Help: "Number of prediction cache entries evicted, by model type and name.",
+	}, cacheMetricLabels)
+	cacheSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "predictive_hpa_prediction_cache_size",
+		Help: "Number of entries currently held in the prediction cache, by model type and name.",
+	}, cacheMetricLabels)
+)
+
+// entry is a single cached prediction result.
+type entry struct {
+	key         string
+	prediction  int32
+	annotations []prediction.Annotation
+	expires     time.Time
+}
+
+// modelCache is the LRU belonging to a single model configuration. Each
+// model gets its own entries/order and is bounded by its own CacheSize, so
+// that one model's predictions can never be evicted to make room for an
+// unrelated model sharing the same Predict instance.
+type modelCache struct {
+	entries map[string]*entry
+	order   []string
+}
+
+// Predict wraps a prediction.Predicter with a keyed, TTL-bounded LRU cache.
+// A cache hit returns the previously calculated prediction without
+// re-invoking the wrapped Predicter. A single Predict instance can be
+// shared across many differently configured models, each partitioned into
+// its own LRU.
+type Predict struct {
+	Predicter prediction.Predicter
+	// Recorder and Object are optional. A cache hit always replays the
+	// annotation-kind metric for the cached result's annotations and, if
+	// both are provided, the Kubernetes Event(s) too, so that annotations
+	// remain visible while a prediction is served from cache. A cache miss
+	// records nothing here; that is left to the wrapped Predicter.
+	Recorder record.EventRecorder
+	Object   runtime.Object
+
+	mutex  sync.Mutex
+	models map[string]*modelCache
+}
+
+// GetIDsToRemove delegates to the wrapped Predicter, invalidating this
+// model's cached predictions if any evaluations are pruned, so that a
+// shrunk history window can never return a stale cached extrapolation.
+func (p *Predict) GetIDsToRemove(model *config.Model, evaluations []*stored.Evaluation) ([]int, error) {
+	ids, err := p.Predicter.GetIDsToRemove(model, evaluations)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) > 0 {
+		mKey, err := modelKey(model)
+		if err != nil {
+			return nil, err
+		}
+		p.invalidate(model, mKey)
+	}
+
+	return ids, nil
+}
+
+// GetPrediction returns a cached prediction if one exists for this model
+// and evaluation set and has not expired, otherwise it runs the wrapped
+// Predicter and caches the result.
+func (p *Predict) GetPrediction(model *config.Model, evaluations []*stored.Evaluation) (int32, []prediction.Annotation, error) {
+	if model.CacheTTL <= 0 || model.CacheSize <= 0 {
+		return p.Predicter.GetPrediction(model, evaluations)
+	}
+
+	mKey, err := modelKey(model)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	key, err := evaluationKey(mKey, evaluations)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if cached, ok := p.get(mKey, key); ok {
+		cacheHits.WithLabelValues(model.Type, model.Name).Inc()
+		prediction.RecordAnnotations(p.Recorder, p.Object, cached.annotations)
+		return cached.prediction, cached.annotations, nil
+	}
+
+	cacheMisses.WithLabelValues(model.Type, model.Name).Inc()
+
+	result, annotations, err := p.Predicter.GetPrediction(model, evaluations)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	p.set(model, mKey, key, result, annotations)
+
+	return result, annotations, nil
+}
+
+// GetType delegates to the wrapped Predicter.
+func (p *Predict) GetType() string {
+	return p.Predicter.GetType()
+}
+
+// get returns a cached entry from the named model's LRU, promoting it to
+// the back of the eviction order so that a key which is repeatedly hit is
+// not evicted as if it were the least recently used.
+func (p *Predict) get(mKey string, key string) (*entry, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	model, ok := p.models[mKey]
+	if !ok {
+		return nil, false
+	}
+
+	cached, ok := model.entries[key]
+	if !ok || time.Now().After(cached.expires) {
+		return nil, false
+	}
+
+	model.promote(key)
+
+	return cached, true
+}
+
+func (p *Predict) set(model *config.Model, mKey string, key string, result int32, annotations []prediction.Annotation) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.models == nil {
+		p.models = map[string]*modelCache{}
+	}
+
+	mc, ok := p.models[mKey]
+	if !ok {
+		mc = &modelCache{entries: map[string]*entry{}}
+		p.models[mKey] = mc
+	}
+
+	if _, exists := mc.entries[key]; !exists {
+		mc.order = append(mc.order, key)
+	} else {
+		mc.promote(key)
+	}
+
+	mc.entries[key] = &entry{
+		key:         key,
+		prediction:  result,
+		annotations: annotations,
+		expires:     time.Now().Add(model.CacheTTL),
+	}
+
+	for len(mc.order) > model.CacheSize {
+		oldest := mc.order[0]
+		mc.order = mc.order[1:]
+		delete(mc.entries, oldest)
+		cacheEvictions.WithLabelValues(model.Type, model.Name).Inc()
+	}
+
+	cacheSize.WithLabelValues(model.Type, model.Name).Set(float64(len(mc.entries)))
+}
+
+// promote moves key to the back of the model's order, marking it as the
+// most recently used entry.
+func (mc *modelCache) promote(key string) {
+	for i, existing := range mc.order {
+		if existing == key {
+			mc.order = append(mc.order[:i], mc.order[i+1:]...)
+			break
+		}
+	}
+	mc.order = append(mc.order, key)
+}
+
+// invalidate clears every cached prediction belonging to this model,
+// leaving predictions cached for other models sharing this Predict
+// instance untouched.
+func (p *Predict) invalidate(model *config.Model, mKey string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	mc, ok := p.models[mKey]
+	if !ok {
+		return
+	}
+
+	evicted := len(mc.entries)
+	delete(p.models, mKey)
+
+	if evicted > 0 {
+		cacheEvictions.WithLabelValues(model.Type, model.Name).Add(float64(evicted))
+	}
+	cacheSize.WithLabelValues(model.Type, model.Name).Set(0)
+}
+
+// modelKey builds a stable hash of a model's type and per-model
+// configuration, used to partition the LRU and scope cache invalidation to
+// a single model.
+func modelKey(model *config.Model) (string, error) {
+	payload, err := json.Marshal(struct {
+		Type   string        `json:"type"`
+		Config *config.Model `json:"config"`
+	}{
+		Type:   model.Type,
+		Config: model,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// evaluationKey builds a stable cache key from a model's key and the
+// ordered list of evaluation IDs, values and creation times fed in, so
+// that two calls with the same inputs always produce the same key, and
+// evaluations that share IDs/values but differ in timing are not
+// conflated.
+func evaluationKey(mKey string, evaluations []*stored.Evaluation) (string, error) {
+	type keyEvaluation struct {
+		ID      int       `json:"id"`
+		Value   int32     `json:"value"`
+		Created time.Time `json:"created"`
+	}
+
+	keyEvaluations := make([]keyEvaluation, len(evaluations))
+	for i, evaluation := range evaluations {
+		keyEvaluations[i] = keyEvaluation{
+			ID:      evaluation.ID,
+			Value:   evaluation.Evaluation.TargetReplicas,
+			Created: evaluation.Created,
+		}
+	}
+
+	payload, err := json.Marshal(struct {
+		ModelKey    string          `json:"modelKey"`
+		Evaluations []keyEvaluation `json:"evaluations"`
+	}{
+		ModelKey:    mKey,
+		Evaluations: keyEvaluations,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
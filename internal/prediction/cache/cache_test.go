@@ -0,0 +1,289 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/config"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/fake"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/cache"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/stored"
+)
+
+func TestPredict_GetPrediction_CachesResult(t *testing.T) {
+	calls := 0
+	predicter := &cache.Predict{
+		Predicter: &fake.Predicter{
+			GetPredictionReactor: func(model *config.Model, evaluations []*stored.Evaluation) (int32, []prediction.Annotation, error) {
+				calls++
+				return 5, nil, nil
+			},
+		},
+	}
+
+	model := &config.Model{
+		Type:      "Linear",
+		CacheTTL:  time.Minute,
+		CacheSize: 10,
+	}
+	evaluations := []*stored.Evaluation{
+		{ID: 0, Evaluation: stored.DBEvaluation{TargetReplicas: 3}},
+	}
+
+	for i := 0; i < 3; i++ {
+		result, _, err := predicter.GetPrediction(model, evaluations)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if result != 5 {
+			t.Errorf("result mismatch, expected 5, got %d", result)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected wrapped Predicter to be called once, called %d times", calls)
+	}
+}
+
+func TestPredict_GetIDsToRemove_InvalidatesCacheOnPrune(t *testing.T) {
+	calls := 0
+	predicter := &cache.Predict{
+		Predicter: &fake.Predicter{
+			GetPredictionReactor: func(model *config.Model, evaluations []*stored.Evaluation) (int32, []prediction.Annotation, error) {
+				calls++
+				return 5, nil, nil
+			},
+			GetIDsToRemoveReactor: func(model *config.Model, evaluations []*stored.Evaluation) ([]int, error) {
+				return []int{0}, nil
+			},
+		},
+	}
+
+	model := &config.Model{
+		Type:      "Linear",
+		CacheTTL:  time.Minute,
+		CacheSize: 10,
+	}
+	evaluations := []*stored.Evaluation{
+		{ID: 0, Evaluation: stored.DBEvaluation{TargetReplicas: 3}},
+	}
+
+	if _, _, err := predicter.GetPrediction(model, evaluations); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := predicter.GetIDsToRemove(model, evaluations); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, _, err := predicter.GetPrediction(model, evaluations); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("expected wrapped Predicter to be called again after prune, called %d times", calls)
+	}
+}
+
+func TestPredict_GetPrediction_ReplaysAnnotationsOnCacheHit(t *testing.T) {
+	annotations := []prediction.Annotation{
+		{Kind: prediction.AnnotationExtrapolated, Message: "extrapolated"},
+	}
+
+	events := 0
+	recorder := &fake.Recorder{
+		EventReactor: func(object runtime.Object, eventtype, reason, message string) {
+			events++
+		},
+	}
+
+	predicter := &cache.Predict{
+		Predicter: &fake.Predicter{
+			GetPredictionReactor: func(model *config.Model, evaluations []*stored.Evaluation) (int32, []prediction.Annotation, error) {
+				// Record the annotations as a real Predicter does on a run.
+				prediction.RecordAnnotations(recorder, &unstructured.Unstructured{}, annotations)
+				return 5, annotations, nil
+			},
+		},
+		Recorder: recorder,
+		Object:   &unstructured.Unstructured{},
+	}
+
+	model := &config.Model{
+		Type:      "Linear",
+		CacheTTL:  time.Minute,
+		CacheSize: 10,
+	}
+	evaluations := []*stored.Evaluation{
+		{ID: 0, Evaluation: stored.DBEvaluation{TargetReplicas: 3}},
+	}
+
+	// First call is a cache miss, handled by the wrapped Predicter.
+	if _, _, err := predicter.GetPrediction(model, evaluations); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Second call is served from the cache, but should still replay the
+	// annotation as a Kubernetes Event.
+	if _, _, err := predicter.GetPrediction(model, evaluations); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if events != 2 {
+		t.Errorf("expected an event to be emitted for both the miss and the hit, got %d events", events)
+	}
+}
+
+func TestPredict_GetPrediction_EvictsLeastRecentlyUsed(t *testing.T) {
+	calls := map[int32]int{}
+	predicter := &cache.Predict{
+		Predicter: &fake.Predicter{
+			GetPredictionReactor: func(model *config.Model, evaluations []*stored.Evaluation) (int32, []prediction.Annotation, error) {
+				id := evaluations[0].Evaluation.TargetReplicas
+				calls[id]++
+				return id, nil, nil
+			},
+		},
+	}
+
+	model := &config.Model{
+		Type:      "Linear",
+		CacheTTL:  time.Minute,
+		CacheSize: 2,
+	}
+	evaluationsFor := func(id int32) []*stored.Evaluation {
+		return []*stored.Evaluation{{ID: 0, Evaluation: stored.DBEvaluation{TargetReplicas: id}}}
+	}
+
+	// Fill the cache with 1 and 2, then re-request 1 so it becomes the most
+	// recently used entry.
+	if _, _, err := predicter.GetPrediction(model, evaluationsFor(1)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, _, err := predicter.GetPrediction(model, evaluationsFor(2)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, _, err := predicter.GetPrediction(model, evaluationsFor(1)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Requesting a third, distinct evaluation set should evict 2, the least
+	// recently used entry, rather than 1.
+	if _, _, err := predicter.GetPrediction(model, evaluationsFor(3)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, _, err := predicter.GetPrediction(model, evaluationsFor(1)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, _, err := predicter.GetPrediction(model, evaluationsFor(2)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if calls[1] != 1 {
+		t.Errorf("expected 1 to remain cached, wrapped Predicter called %d times", calls[1])
+	}
+	if calls[2] != 2 {
+		t.Errorf("expected 2 to have been evicted and recalculated, wrapped Predicter called %d times", calls[2])
+	}
+	if calls[3] != 1 {
+		t.Errorf("expected 3 to be cached after first call, wrapped Predicter called %d times", calls[3])
+	}
+}
+
+func TestPredict_GetPrediction_ExpiresAfterTTL(t *testing.T) {
+	calls := 0
+	predicter := &cache.Predict{
+		Predicter: &fake.Predicter{
+			GetPredictionReactor: func(model *config.Model, evaluations []*stored.Evaluation) (int32, []prediction.Annotation, error) {
+				calls++
+				return 5, nil, nil
+			},
+		},
+	}
+
+	model := &config.Model{
+		Type:      "Linear",
+		CacheTTL:  time.Millisecond,
+		CacheSize: 10,
+	}
+	evaluations := []*stored.Evaluation{
+		{ID: 0, Evaluation: stored.DBEvaluation{TargetReplicas: 3}},
+	}
+
+	if _, _, err := predicter.GetPrediction(model, evaluations); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	if _, _, err := predicter.GetPrediction(model, evaluations); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("expected wrapped Predicter to be called again after TTL expiry, called %d times", calls)
+	}
+}
+
+func TestPredict_GetPrediction_PartitionsCacheByModel(t *testing.T) {
+	calls := map[string]int{}
+	predicter := &cache.Predict{
+		Predicter: &fake.Predicter{
+			GetPredictionReactor: func(model *config.Model, evaluations []*stored.Evaluation) (int32, []prediction.Annotation, error) {
+				calls[model.Name]++
+				return 5, nil, nil
+			},
+		},
+	}
+
+	big := &config.Model{Type: "Linear", Name: "big", CacheTTL: time.Minute, CacheSize: 100}
+	small := &config.Model{Type: "Linear", Name: "small", CacheTTL: time.Minute, CacheSize: 1}
+
+	evaluationsFor := func(id int) []*stored.Evaluation {
+		return []*stored.Evaluation{{ID: id, Evaluation: stored.DBEvaluation{TargetReplicas: int32(id)}}}
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, _, err := predicter.GetPrediction(big, evaluationsFor(i)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	// "small" has a CacheSize of 1, far smaller than the 10 entries already
+	// cached for "big". Requesting a prediction for "small" must not evict
+	// any of "big"'s entries.
+	if _, _, err := predicter.GetPrediction(small, evaluationsFor(0)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, _, err := predicter.GetPrediction(big, evaluationsFor(i)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if calls["big"] != 10 {
+		t.Errorf("expected wrapped Predicter to be called once per distinct \"big\" evaluation set, called %d times", calls["big"])
+	}
+}
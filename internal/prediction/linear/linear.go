@@ -0,0 +1,238 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package linear provides a linear regression prediction model, training
+// against values stored from previous evaluations.
+package linear
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/config"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/stored"
+)
+
+// Type is the identifier used for the linear regression prediction model.
+const Type = "Linear"
+
+const algorithmPath = "/app/algorithms/linear_regression/predict.py"
+const algorithmTimeout = 30000
+
+// Runner defines behaviour required to run the Python linear regression
+// algorithm and retrieve its result.
+type Runner interface {
+	RunAlgorithmWithValue(algorithmPath string, value string, timeout int) (string, error)
+}
+
+// Gatherer defines behaviour required to seed a model with historical
+// evaluations from an external source, such as Prometheus, used to train
+// against before enough evaluations have been stored locally.
+type Gatherer interface {
+	Gather(prometheus *config.Prometheus) ([]*stored.Evaluation, error)
+}
+
+// algorithmRequest is the JSON payload piped into the linear regression
+// algorithm, containing the model's configuration and the stored
+// evaluations to train against.
+type algorithmRequest struct {
+	StoredValues int                  `json:"storedValues"`
+	LookAhead    int                  `json:"lookAhead"`
+	Evaluations  []*stored.Evaluation `json:"evaluations"`
+}
+
+// algorithmResponse is the JSON payload returned by the linear regression
+// algorithm, containing the predicted replica count alongside the
+// goodness-of-fit of the regression it was calculated from.
+type algorithmResponse struct {
+	TargetReplicas int32   `json:"targetReplicas"`
+	RSquared       float64 `json:"rSquared"`
+}
+
+// Predict provides logic for predicting future resource load using linear
+// regression.
+type Predict struct {
+	Runner   Runner
+	Gatherer Gatherer
+	// Recorder and Object are optional; if both are provided, a Kubernetes
+	// Event is emitted on Object for every annotation produced by a
+	// prediction run, alongside the annotation-kind metric that is always
+	// recorded.
+	Recorder record.EventRecorder
+	Object   runtime.Object
+}
+
+// GetIDsToRemove provides the list of evaluation IDs that should be pruned,
+// keeping only the most recently created `StoredValues` evaluations.
+func (p *Predict) GetIDsToRemove(model *config.Model, evaluations []*stored.Evaluation) ([]int, error) {
+	if model.Linear == nil {
+		return nil, errors.New("No Linear configuration provided for model")
+	}
+
+	if len(evaluations) <= model.Linear.StoredValues {
+		return nil, nil
+	}
+
+	sorted := make([]*stored.Evaluation, len(evaluations))
+	copy(sorted, evaluations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Created.Before(sorted[j].Created)
+	})
+
+	toRemove := len(sorted) - model.Linear.StoredValues
+	ids := make([]int, toRemove)
+	for i := 0; i < toRemove; i++ {
+		ids[i] = sorted[i].ID
+	}
+
+	return ids, nil
+}
+
+// GetPrediction predicts the number of replicas required based on a linear
+// regression run against the provided stored evaluations. Alongside the
+// prediction it returns any annotations describing non-fatal conditions
+// encountered while producing the result.
+func (p *Predict) GetPrediction(model *config.Model, evaluations []*stored.Evaluation) (int32, []prediction.Annotation, error) {
+	if model.Linear == nil {
+		return 0, nil, errors.New("No Linear configuration provided for model")
+	}
+
+	if model.Prometheus != nil && p.Gatherer != nil {
+		historic, err := p.Gatherer.Gather(model.Prometheus)
+		if err != nil {
+			return 0, nil, err
+		}
+		evaluations = append(historic, evaluations...)
+	}
+
+	if len(evaluations) == 0 {
+		return 0, nil, errors.New("No evaluations provided for Linear regression model")
+	}
+
+	var annotations []prediction.Annotation
+	if len(evaluations) < model.Linear.StoredValues {
+		annotations = append(annotations, prediction.Annotation{
+			Kind:    prediction.AnnotationExtrapolated,
+			Message: fmt.Sprintf("fewer than StoredValues samples (%d of %d), prediction extrapolated", len(evaluations), model.Linear.StoredValues),
+		})
+	}
+
+	if model.Linear.LookAhead > len(evaluations) {
+		annotations = append(annotations, prediction.Annotation{
+			Kind:    prediction.AnnotationLookAheadExceedsRange,
+			Message: fmt.Sprintf("LookAhead (%d) exceeds the %d observed evaluations, prediction extrapolated beyond observed range", model.Linear.LookAhead, len(evaluations)),
+		})
+	}
+
+	var predicted int32
+	if len(evaluations) == 1 {
+		predicted = evaluations[0].Evaluation.TargetReplicas
+	} else {
+		request, err := json.Marshal(algorithmRequest{
+			StoredValues: model.Linear.StoredValues,
+			LookAhead:    model.Linear.LookAhead,
+			Evaluations:  evaluations,
+		})
+		if err != nil {
+			return 0, nil, err
+		}
+
+		result, err := p.Runner.RunAlgorithmWithValue(algorithmPath, string(request), algorithmTimeout)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		var response algorithmResponse
+		if err := json.Unmarshal([]byte(result), &response); err != nil {
+			return 0, nil, err
+		}
+
+		if model.Linear.RSquaredThreshold > 0 && response.RSquared < model.Linear.RSquaredThreshold {
+			annotations = append(annotations, prediction.Annotation{
+				Kind:    prediction.AnnotationRegressionFit,
+				Message: fmt.Sprintf("regression R² (%.4f) is below the configured threshold (%.4f), prediction may be unreliable", response.RSquared, model.Linear.RSquaredThreshold),
+			})
+		}
+
+		predicted = response.TargetReplicas
+	}
+
+	scope := model.Scope
+	if scope == "" {
+		scope = config.ScopeBoth
+	}
+
+	if scope != config.ScopeBoth {
+		recent := mostRecentEvaluation(evaluations)
+		switch {
+		case predicted > recent.Evaluation.TargetReplicas && scope != config.ScopeScaleUp:
+			predicted = recent.Evaluation.TargetReplicas
+			annotations = append(annotations, prediction.Annotation{
+				Kind:    prediction.AnnotationModelSkipped,
+				Message: fmt.Sprintf("model scope is %q, skipped as prediction would scale up", scope),
+			})
+		case predicted < recent.Evaluation.TargetReplicas && scope != config.ScopeScaleDown:
+			predicted = recent.Evaluation.TargetReplicas
+			annotations = append(annotations, prediction.Annotation{
+				Kind:    prediction.AnnotationModelSkipped,
+				Message: fmt.Sprintf("model scope is %q, skipped as prediction would scale down", scope),
+			})
+		}
+	}
+
+	switch {
+	case model.Linear.MaxReplicas > 0 && predicted > model.Linear.MaxReplicas:
+		annotations = append(annotations, prediction.Annotation{
+			Kind:    prediction.AnnotationValueClamped,
+			Message: fmt.Sprintf("prediction (%d) clamped to MaxReplicas (%d)", predicted, model.Linear.MaxReplicas),
+		})
+		predicted = model.Linear.MaxReplicas
+	case model.Linear.MinReplicas > 0 && predicted < model.Linear.MinReplicas:
+		annotations = append(annotations, prediction.Annotation{
+			Kind:    prediction.AnnotationValueClamped,
+			Message: fmt.Sprintf("prediction (%d) clamped to MinReplicas (%d)", predicted, model.Linear.MinReplicas),
+		})
+		predicted = model.Linear.MinReplicas
+	}
+
+	prediction.RecordAnnotations(p.Recorder, p.Object, annotations)
+
+	return predicted, annotations, nil
+}
+
+// mostRecentEvaluation returns the evaluation with the latest Created time,
+// used as the current replica count to compare a prediction's direction
+// against when the model is scoped to scale up or scale down only.
+func mostRecentEvaluation(evaluations []*stored.Evaluation) *stored.Evaluation {
+	recent := evaluations[0]
+	for _, evaluation := range evaluations[1:] {
+		if evaluation.Created.After(recent.Created) {
+			recent = evaluation
+		}
+	}
+	return recent
+}
+
+// GetType returns the type of the prediction model.
+func (p *Predict) GetType() string {
+	return Type
+}
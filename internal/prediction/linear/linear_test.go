@@ -24,6 +24,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/config"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/fake"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction/linear"
 	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/stored"
 )
@@ -37,16 +38,18 @@ func TestPredict_GetPrediction(t *testing.T) {
 	})
 
 	var tests = []struct {
-		description string
-		expected    int32
-		expectedErr error
-		predicter   *linear.Predict
-		model       *config.Model
-		evaluations []*stored.Evaluation
+		description         string
+		expected            int32
+		expectedAnnotations []prediction.Annotation
+		expectedErr         error
+		predicter           *linear.Predict
+		model               *config.Model
+		evaluations         []*stored.Evaluation
 	}{
 		{
 			"Fail no Linear configuration",
 			0,
+			nil,
 			errors.New("No Linear configuration provided for model"),
 			&linear.Predict{},
 			&config.Model{},
@@ -55,6 +58,7 @@ func TestPredict_GetPrediction(t *testing.T) {
 		{
 			"Fail no evaluations",
 			0,
+			nil,
 			errors.New("No evaluations provided for Linear regression model"),
 			&linear.Predict{},
 			&config.Model{
@@ -69,6 +73,12 @@ func TestPredict_GetPrediction(t *testing.T) {
 		{
 			"Success, only one evaluation, return without the prediction",
 			32,
+			[]prediction.Annotation{
+				{
+					Kind:    prediction.AnnotationExtrapolated,
+					Message: "fewer than StoredValues samples (1 of 5), prediction extrapolated",
+				},
+			},
 			nil,
 			&linear.Predict{},
 			&config.Model{
@@ -90,6 +100,7 @@ func TestPredict_GetPrediction(t *testing.T) {
 		{
 			"Fail execution of algorithm fails",
 			0,
+			nil,
 			errors.New("algorithm fail"),
 			&linear.Predict{
 				Runner: &fake.Run{
@@ -115,9 +126,10 @@ func TestPredict_GetPrediction(t *testing.T) {
 			},
 		},
 		{
-			"Fail algorithm returns non-integer castable value",
+			"Fail algorithm returns invalid JSON",
 			0,
-			errors.New(`strconv.Atoi: parsing "invalid": invalid syntax`),
+			nil,
+			errors.New("invalid character 'i' looking for beginning of value"),
 			&linear.Predict{
 				Runner: &fake.Run{
 					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
@@ -144,11 +156,384 @@ func TestPredict_GetPrediction(t *testing.T) {
 		{
 			"Success",
 			3,
+			[]prediction.Annotation{
+				{
+					Kind:    prediction.AnnotationExtrapolated,
+					Message: "fewer than StoredValues samples (2 of 5), prediction extrapolated",
+				},
+			},
+			nil,
+			&linear.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return `{"targetReplicas":3}`, nil
+					},
+				},
+			},
+			&config.Model{
+				Type: linear.Type,
+				Linear: &config.Linear{
+					StoredValues: 5,
+					LookAhead:    0,
+				},
+			},
+			[]*stored.Evaluation{
+				{
+					ID: 0,
+				},
+				{
+					ID: 1,
+				},
+			},
+		},
+		{
+			"Success, ScaleUp scope, increasing history, model applied",
+			5,
+			[]prediction.Annotation{
+				{
+					Kind:    prediction.AnnotationExtrapolated,
+					Message: "fewer than StoredValues samples (2 of 5), prediction extrapolated",
+				},
+			},
+			nil,
+			&linear.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return `{"targetReplicas":5}`, nil
+					},
+				},
+			},
+			&config.Model{
+				Type:  linear.Type,
+				Scope: config.ScopeScaleUp,
+				Linear: &config.Linear{
+					StoredValues: 5,
+					LookAhead:    0,
+				},
+			},
+			[]*stored.Evaluation{
+				{
+					ID:      0,
+					Created: time.Time{}.Add(time.Duration(1) * time.Second),
+					Evaluation: stored.DBEvaluation{
+						TargetReplicas: 2,
+					},
+				},
+				{
+					ID:      1,
+					Created: time.Time{}.Add(time.Duration(2) * time.Second),
+					Evaluation: stored.DBEvaluation{
+						TargetReplicas: 3,
+					},
+				},
+			},
+		},
+		{
+			"Success, ScaleUp scope, decreasing history, model skipped",
+			3,
+			[]prediction.Annotation{
+				{
+					Kind:    prediction.AnnotationExtrapolated,
+					Message: "fewer than StoredValues samples (2 of 5), prediction extrapolated",
+				},
+				{
+					Kind:    prediction.AnnotationModelSkipped,
+					Message: `model scope is "ScaleUp", skipped as prediction would scale down`,
+				},
+			},
+			nil,
+			&linear.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return `{"targetReplicas":1}`, nil
+					},
+				},
+			},
+			&config.Model{
+				Type:  linear.Type,
+				Scope: config.ScopeScaleUp,
+				Linear: &config.Linear{
+					StoredValues: 5,
+					LookAhead:    0,
+				},
+			},
+			[]*stored.Evaluation{
+				{
+					ID:      0,
+					Created: time.Time{}.Add(time.Duration(1) * time.Second),
+					Evaluation: stored.DBEvaluation{
+						TargetReplicas: 4,
+					},
+				},
+				{
+					ID:      1,
+					Created: time.Time{}.Add(time.Duration(2) * time.Second),
+					Evaluation: stored.DBEvaluation{
+						TargetReplicas: 3,
+					},
+				},
+			},
+		},
+		{
+			"Success, ScaleDown scope, decreasing history, model applied",
+			1,
+			[]prediction.Annotation{
+				{
+					Kind:    prediction.AnnotationExtrapolated,
+					Message: "fewer than StoredValues samples (2 of 5), prediction extrapolated",
+				},
+			},
+			nil,
+			&linear.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return `{"targetReplicas":1}`, nil
+					},
+				},
+			},
+			&config.Model{
+				Type:  linear.Type,
+				Scope: config.ScopeScaleDown,
+				Linear: &config.Linear{
+					StoredValues: 5,
+					LookAhead:    0,
+				},
+			},
+			[]*stored.Evaluation{
+				{
+					ID:      0,
+					Created: time.Time{}.Add(time.Duration(1) * time.Second),
+					Evaluation: stored.DBEvaluation{
+						TargetReplicas: 4,
+					},
+				},
+				{
+					ID:      1,
+					Created: time.Time{}.Add(time.Duration(2) * time.Second),
+					Evaluation: stored.DBEvaluation{
+						TargetReplicas: 3,
+					},
+				},
+			},
+		},
+		{
+			"Success, ScaleDown scope, increasing history, model skipped",
+			3,
+			[]prediction.Annotation{
+				{
+					Kind:    prediction.AnnotationExtrapolated,
+					Message: "fewer than StoredValues samples (2 of 5), prediction extrapolated",
+				},
+				{
+					Kind:    prediction.AnnotationModelSkipped,
+					Message: `model scope is "ScaleDown", skipped as prediction would scale up`,
+				},
+			},
+			nil,
+			&linear.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return `{"targetReplicas":5}`, nil
+					},
+				},
+			},
+			&config.Model{
+				Type:  linear.Type,
+				Scope: config.ScopeScaleDown,
+				Linear: &config.Linear{
+					StoredValues: 5,
+					LookAhead:    0,
+				},
+			},
+			[]*stored.Evaluation{
+				{
+					ID:      0,
+					Created: time.Time{}.Add(time.Duration(1) * time.Second),
+					Evaluation: stored.DBEvaluation{
+						TargetReplicas: 2,
+					},
+				},
+				{
+					ID:      1,
+					Created: time.Time{}.Add(time.Duration(2) * time.Second),
+					Evaluation: stored.DBEvaluation{
+						TargetReplicas: 3,
+					},
+				},
+			},
+		},
+		{
+			"Success, Prometheus historical evaluations merged in before the algorithm runs",
+			10,
+			nil,
+			nil,
+			&linear.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return `{"targetReplicas":10}`, nil
+					},
+				},
+				Gatherer: &fake.Gatherer{
+					GatherReactor: func(prometheus *config.Prometheus) ([]*stored.Evaluation, error) {
+						return []*stored.Evaluation{
+							{ID: -3},
+							{ID: -2},
+							{ID: -1},
+						}, nil
+					},
+				},
+			},
+			&config.Model{
+				Type: linear.Type,
+				Linear: &config.Linear{
+					StoredValues: 5,
+					LookAhead:    0,
+				},
+				Prometheus: &config.Prometheus{
+					Host:  "http://prometheus",
+					Query: "up",
+				},
+			},
+			[]*stored.Evaluation{
+				{
+					ID: 0,
+				},
+				{
+					ID: 1,
+				},
+			},
+		},
+		{
+			"Success, LookAhead exceeds observed evaluations",
+			3,
+			[]prediction.Annotation{
+				{
+					Kind:    prediction.AnnotationExtrapolated,
+					Message: "fewer than StoredValues samples (2 of 5), prediction extrapolated",
+				},
+				{
+					Kind:    prediction.AnnotationLookAheadExceedsRange,
+					Message: "LookAhead (3) exceeds the 2 observed evaluations, prediction extrapolated beyond observed range",
+				},
+			},
+			nil,
+			&linear.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return `{"targetReplicas":3}`, nil
+					},
+				},
+			},
+			&config.Model{
+				Type: linear.Type,
+				Linear: &config.Linear{
+					StoredValues: 5,
+					LookAhead:    3,
+				},
+			},
+			[]*stored.Evaluation{
+				{
+					ID: 0,
+				},
+				{
+					ID: 1,
+				},
+			},
+		},
+		{
+			"Success, regression R² below configured threshold",
+			3,
+			[]prediction.Annotation{
+				{
+					Kind:    prediction.AnnotationExtrapolated,
+					Message: "fewer than StoredValues samples (2 of 5), prediction extrapolated",
+				},
+				{
+					Kind:    prediction.AnnotationRegressionFit,
+					Message: "regression R² (0.5000) is below the configured threshold (0.9000), prediction may be unreliable",
+				},
+			},
+			nil,
+			&linear.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return `{"targetReplicas":3,"rSquared":0.5}`, nil
+					},
+				},
+			},
+			&config.Model{
+				Type: linear.Type,
+				Linear: &config.Linear{
+					StoredValues:      5,
+					LookAhead:         0,
+					RSquaredThreshold: 0.9,
+				},
+			},
+			[]*stored.Evaluation{
+				{
+					ID: 0,
+				},
+				{
+					ID: 1,
+				},
+			},
+		},
+		{
+			"Success, prediction clamped to MaxReplicas",
+			10,
+			[]prediction.Annotation{
+				{
+					Kind:    prediction.AnnotationExtrapolated,
+					Message: "fewer than StoredValues samples (2 of 5), prediction extrapolated",
+				},
+				{
+					Kind:    prediction.AnnotationValueClamped,
+					Message: "prediction (20) clamped to MaxReplicas (10)",
+				},
+			},
+			nil,
+			&linear.Predict{
+				Runner: &fake.Run{
+					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
+						return `{"targetReplicas":20}`, nil
+					},
+				},
+			},
+			&config.Model{
+				Type: linear.Type,
+				Linear: &config.Linear{
+					StoredValues: 5,
+					LookAhead:    0,
+					MaxReplicas:  10,
+				},
+			},
+			[]*stored.Evaluation{
+				{
+					ID: 0,
+				},
+				{
+					ID: 1,
+				},
+			},
+		},
+		{
+			"Success, prediction clamped to MinReplicas",
+			2,
+			[]prediction.Annotation{
+				{
+					Kind:    prediction.AnnotationExtrapolated,
+					Message: "fewer than StoredValues samples (2 of 5), prediction extrapolated",
+				},
+				{
+					Kind:    prediction.AnnotationValueClamped,
+					Message: "prediction (1) clamped to MinReplicas (2)",
+				},
+			},
 			nil,
 			&linear.Predict{
 				Runner: &fake.Run{
 					RunAlgorithmWithValueReactor: func(algorithmPath, value string, timeout int) (string, error) {
-						return "3", nil
+						return `{"targetReplicas":1}`, nil
 					},
 				},
 			},
@@ -157,6 +542,7 @@ func TestPredict_GetPrediction(t *testing.T) {
 				Linear: &config.Linear{
 					StoredValues: 5,
 					LookAhead:    0,
+					MinReplicas:  2,
 				},
 			},
 			[]*stored.Evaluation{
@@ -171,7 +557,7 @@ func TestPredict_GetPrediction(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.description, func(t *testing.T) {
-			result, err := test.predicter.GetPrediction(test.model, test.evaluations)
+			result, annotations, err := test.predicter.GetPrediction(test.model, test.evaluations)
 			if !cmp.Equal(&err, &test.expectedErr, equateErrorMessage) {
 				t.Errorf("error mismatch (-want +got):\n%s", cmp.Diff(test.expectedErr, err, equateErrorMessage))
 				return
@@ -179,6 +565,9 @@ func TestPredict_GetPrediction(t *testing.T) {
 			if !cmp.Equal(test.expected, result) {
 				t.Errorf("result mismatch (-want +got):\n%s", cmp.Diff(test.expected, result))
 			}
+			if !cmp.Equal(test.expectedAnnotations, annotations) {
+				t.Errorf("annotations mismatch (-want +got):\n%s", cmp.Diff(test.expectedAnnotations, annotations))
+			}
 		})
 	}
 }
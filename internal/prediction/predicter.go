@@ -0,0 +1,33 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package prediction defines the interface that prediction models must
+// implement, allowing the predictive horizontal pod autoscaler to combine
+// multiple differing prediction strategies.
+package prediction
+
+import (
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/config"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/stored"
+)
+
+// Predicter defines logic that should be followed to predict future
+// resource load based on a model and a set of previously stored evaluations.
+type Predicter interface {
+	GetIDsToRemove(model *config.Model, evaluations []*stored.Evaluation) ([]int, error)
+	GetPrediction(model *config.Model, evaluations []*stored.Evaluation) (int32, []Annotation, error)
+	GetType() string
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prediction
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+var annotationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "predictive_hpa_prediction_annotations_total",
+	Help: "Number of non-fatal annotations raised by prediction models, by annotation kind.",
+}, []string{"kind"})
+
+// RecordAnnotations updates the annotation-kind metric for each provided
+// annotation, and, if recorder and object are both provided, emits a
+// Kubernetes Event on object for each one too. This gives operators a way
+// to see why a predictor is behaving unexpectedly without turning on
+// debug logging.
+func RecordAnnotations(recorder record.EventRecorder, object runtime.Object, annotations []Annotation) {
+	for _, annotation := range annotations {
+		annotationsTotal.WithLabelValues(string(annotation.Kind)).Inc()
+		if recorder != nil && object != nil {
+			recorder.Event(object, corev1.EventTypeNormal, string(annotation.Kind), annotation.Message)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prediction_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/fake"
+	"github.com/jthomperoo/predictive-horizontal-pod-autoscaler/internal/prediction"
+)
+
+func TestRecordAnnotations(t *testing.T) {
+	var tests = []struct {
+		description   string
+		recorder      *fake.Recorder
+		object        runtime.Object
+		annotations   []prediction.Annotation
+		expectedCalls int
+	}{
+		{
+			"No annotations, no events",
+			&fake.Recorder{},
+			&unstructured.Unstructured{},
+			nil,
+			0,
+		},
+		{
+			"No recorder provided, no events",
+			nil,
+			&unstructured.Unstructured{},
+			[]prediction.Annotation{
+				{Kind: prediction.AnnotationExtrapolated, Message: "extrapolated"},
+			},
+			0,
+		},
+		{
+			"Annotations provided with recorder, event emitted per annotation",
+			&fake.Recorder{},
+			&unstructured.Unstructured{},
+			[]prediction.Annotation{
+				{Kind: prediction.AnnotationExtrapolated, Message: "extrapolated"},
+				{Kind: prediction.AnnotationModelSkipped, Message: "skipped"},
+			},
+			2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			calls := 0
+
+			// Only assign to the record.EventRecorder interface when a fake
+			// is actually provided, otherwise passing a typed nil
+			// *fake.Recorder produces a non-nil interface value and
+			// RecordAnnotations would try to call through it.
+			var recorder record.EventRecorder
+			if test.recorder != nil {
+				test.recorder.EventReactor = func(object runtime.Object, eventtype, reason, message string) {
+					calls++
+					if eventtype != corev1.EventTypeNormal {
+						t.Errorf("eventtype mismatch, expected %s, got %s", corev1.EventTypeNormal, eventtype)
+					}
+				}
+				recorder = test.recorder
+			}
+
+			prediction.RecordAnnotations(recorder, test.object, test.annotations)
+
+			if calls != test.expectedCalls {
+				t.Errorf("calls mismatch, expected %d, got %d", test.expectedCalls, calls)
+			}
+		})
+	}
+}
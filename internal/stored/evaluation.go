@@ -0,0 +1,36 @@
+/*
+Copyright 2021 The Predictive Horizontal Pod Autoscaler Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package stored provides definitions for how evaluations are persisted
+// between reconciles, allowing prediction models to be trained against
+// historical data.
+package stored
+
+import "time"
+
+// DBEvaluation is the part of an Evaluation that gets marshalled into the
+// database, holding only the replica count that was calculated.
+type DBEvaluation struct {
+	TargetReplicas int32 `json:"targetReplicas"`
+}
+
+// Evaluation is a stored evaluation, tagged with an ID used to prune old
+// values and a creation time used to order them for prediction.
+type Evaluation struct {
+	ID         int          `json:"id"`
+	Created    time.Time    `json:"created"`
+	Evaluation DBEvaluation `json:"evaluation"`
+}